@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// podPatch is the strategic merge patch body applied to a Pod. It is also
+// what --dry-run prints, so operators can see exactly what would change.
+type podPatch struct {
+	Metadata podPatchMetadata `json:"metadata"`
+}
+
+type podPatchMetadata struct {
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// addAnnotation patches the pod with the given annotations and labels. The
+// patch is a strategic merge, so applying the same values twice leaves the
+// pod in the same state, which is what lets reconcile retry freely. In
+// dry-run mode the patch is printed instead of sent to the API server.
+func addAnnotation(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string, annotations, labels map[string]string, dryRun bool) error {
+	patch := podPatch{Metadata: podPatchMetadata{Annotations: annotations, Labels: labels}}
+	raw, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("encoding patch for %s/%s: %w", namespace, name, err)
+	}
+
+	if dryRun {
+		fmt.Printf("[dry-run] pod %s/%s patch: %s\n", namespace, name, raw)
+		return nil
+	}
+
+	_, err = clientset.CoreV1().Pods(namespace).Patch(ctx, name, types.StrategicMergePatchType, raw, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("patching pod %s/%s: %w", namespace, name, err)
+	}
+
+	fmt.Printf("Pod %s/%s successfully patched: %s\n", namespace, name, raw)
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// newApplyCommand builds the `pod-annotator apply` subcommand, which reads
+// one or more PodAnnotationPolicy documents and runs a single reconcile
+// pass over every Pod currently matching each one. It's meant to be run
+// from CI, e.g. `kubectl pod-annotator apply -f policies/`.
+func newApplyCommand() *cobra.Command {
+	var (
+		policyFiles []string
+		applyDryRun bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply one or more PodAnnotationPolicy documents",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policies, err := loadPolicies(policyFiles)
+			if err != nil {
+				return err
+			}
+			for _, policy := range policies {
+				if err := policy.validate(); err != nil {
+					return fmt.Errorf("policy %q: %w", policy.Metadata.Name, err)
+				}
+			}
+
+			config, err := clientcmd.BuildConfigFromFlags("", os.Getenv("KUBECONFIG"))
+			if err != nil {
+				config, err = rest.InClusterConfig()
+				if err != nil {
+					return fmt.Errorf("loading kubeconfig: %w", err)
+				}
+			}
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("building clientset: %w", err)
+			}
+			dynClient, err := dynamic.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("building dynamic client: %w", err)
+			}
+
+			for _, policy := range policies {
+				if err := applyPolicy(cmd.Context(), clientset, dynClient, policy, applyDryRun); err != nil {
+					return fmt.Errorf("applying policy %q: %w", policy.Metadata.Name, err)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&policyFiles, "file", "f", nil, "Policy file or directory to apply (repeatable)")
+	cmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "Print the JSON patch for each matching Pod instead of applying it")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// applyPolicy annotates every Pod currently matching policy. Unlike `run`,
+// this does a single List rather than starting an informer, since apply is
+// meant to be a one-shot pass.
+func applyPolicy(ctx context.Context, clientset *kubernetes.Clientset, dynClient dynamic.Interface, policy PodAnnotationPolicy, dryRun bool) error {
+	nodes := newStaticNodeSet(policy.Spec.NodeSelector)
+	cfg := &annotationConfig{Annotations: policy.Spec.Annotations, Labels: policy.Spec.Labels}
+	owners := newOwnerResolver(dynClient, policy.Spec.OwnerKinds)
+
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{LabelSelector: policy.Spec.PodSelector})
+	if err != nil {
+		return fmt.Errorf("listing pods: %w", err)
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !nodes.Has(pod.Spec.NodeName) {
+			continue
+		}
+
+		owned, err := owners.Matches(ctx, pod)
+		if err != nil {
+			return err
+		}
+		if !owned {
+			continue
+		}
+
+		annotations, labels, err := renderAnnotations(cfg, pod)
+		if err != nil {
+			return err
+		}
+		if err := addAnnotation(ctx, clientset, pod.Namespace, pod.Name, annotations, labels, dryRun); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// newGenerateCommand builds the `pod-annotator generate` subcommand, which
+// emits a PodAnnotationPolicy representing what `run` with the same flags
+// would do. The policy can be checked into git and replayed later with
+// `pod-annotator apply -f`.
+func newGenerateCommand() *cobra.Command {
+	var (
+		genName            string
+		genFile            string
+		genLabel           string
+		genOwnerKinds      []string
+		genAnnotationsFile string
+		genAnnotation      []string
+		genPodLabel        []string
+		genOutput          string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a PodAnnotationPolicy from flags",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nodes, err := readNodeNames(genFile)
+			if err != nil {
+				return fmt.Errorf("reading node list: %w", err)
+			}
+
+			cfg, err := loadAnnotationConfig(genAnnotationsFile)
+			if err != nil {
+				return err
+			}
+			if err := cfg.applyAnnotationFlags(genAnnotation); err != nil {
+				return fmt.Errorf("parsing --annotation: %w", err)
+			}
+			if err := cfg.applyLabelFlags(genPodLabel); err != nil {
+				return fmt.Errorf("parsing --pod-label: %w", err)
+			}
+
+			policy := PodAnnotationPolicy{
+				APIVersion: policyAPIVersion,
+				Kind:       policyKind,
+				Metadata:   PolicyMetadata{Name: genName},
+				Spec: PodAnnotationPolicySpec{
+					NodeSelector: nodes,
+					PodSelector:  genLabel,
+					OwnerKinds:   genOwnerKinds,
+					Annotations:  cfg.Annotations,
+					Labels:       cfg.Labels,
+				},
+			}
+
+			out, err := yaml.Marshal(policy)
+			if err != nil {
+				return fmt.Errorf("encoding policy: %w", err)
+			}
+
+			if genOutput == "" {
+				_, err = os.Stdout.Write(out)
+				return err
+			}
+			return os.WriteFile(genOutput, out, 0o644)
+		},
+	}
+
+	cmd.Flags().StringVar(&genName, "name", "pod-annotator", "metadata.name for the generated policy")
+	cmd.Flags().StringVarP(&genFile, "file", "f", "", "Filename containing the node list (required)")
+	cmd.Flags().StringVarP(&genLabel, "label", "l", "", "Label selector for filtering Pods (required)")
+	cmd.Flags().StringArrayVar(&genOwnerKinds, "owner-kind", nil, "Workload kind a Pod must be owned by (repeatable, default CloneSet)")
+	cmd.Flags().StringVar(&genAnnotationsFile, "annotations-file", "", "YAML/JSON file of annotations/labels to apply (see annotationConfig)")
+	cmd.Flags().StringArrayVar(&genAnnotation, "annotation", nil, "Annotation to apply, as key=value (repeatable, overrides --annotations-file)")
+	cmd.Flags().StringArrayVar(&genPodLabel, "pod-label", nil, "Label to apply, as key=value (repeatable)")
+	cmd.Flags().StringVarP(&genOutput, "output", "o", "", "Write the policy here instead of stdout")
+
+	cmd.MarkFlagRequired("file")
+	cmd.MarkFlagRequired("label")
+
+	return cmd
+}
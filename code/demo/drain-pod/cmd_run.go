@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// newRunCommand builds the `pod-annotator run` subcommand, which starts the
+// long-running informer/workqueue controller described by its flags. This
+// is the original default behavior of pod-annotator, now one of several
+// subcommands alongside generate/apply/validate.
+func newRunCommand() *cobra.Command {
+	var (
+		filename        string
+		labelSelector   string
+		resyncPeriod    time.Duration
+		workerCount     int
+		leaderElect     bool
+		metricsAddr     string
+		healthAddr      string
+		annotationsFile string
+		annotationFlags []string
+		podLabelFlags   []string
+		dryRun          bool
+		ownerKinds      []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the long-running controller that watches and annotates Pods",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := clientcmd.BuildConfigFromFlags("", os.Getenv("KUBECONFIG"))
+			if err != nil {
+				config, err = rest.InClusterConfig()
+				if err != nil {
+					return fmt.Errorf("loading kubeconfig: %w", err)
+				}
+			}
+
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("building clientset: %w", err)
+			}
+
+			dynClient, err := dynamic.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("building dynamic client: %w", err)
+			}
+
+			nodes, err := newNodeSet(filename)
+			if err != nil {
+				return fmt.Errorf("loading node list: %w", err)
+			}
+			defer nodes.Close()
+
+			cfg, err := loadAnnotationConfig(annotationsFile)
+			if err != nil {
+				return fmt.Errorf("loading annotation config: %w", err)
+			}
+			if err := cfg.applyAnnotationFlags(annotationFlags); err != nil {
+				return fmt.Errorf("parsing --annotation: %w", err)
+			}
+			if err := cfg.applyLabelFlags(podLabelFlags); err != nil {
+				return fmt.Errorf("parsing --pod-label: %w", err)
+			}
+
+			owners := newOwnerResolver(dynClient, ownerKinds)
+
+			ctrl, err := newController(clientset, nodes, cfg, owners, dryRun, labelSelector, resyncPeriod, workerCount)
+			if err != nil {
+				return fmt.Errorf("building controller: %w", err)
+			}
+
+			serveMetricsAndHealth(metricsAddr, healthAddr, ctrl)
+
+			ctx := signalContext()
+
+			if !leaderElect {
+				ctrl.Run(ctx)
+				return nil
+			}
+
+			return runWithLeaderElection(ctx, clientset, ctrl)
+		},
+	}
+
+	cmd.Flags().StringVarP(&filename, "file", "f", "", "Filename containing the node list (required)")
+	cmd.Flags().StringVarP(&labelSelector, "label", "l", "", "Label selector for filtering Pods (required)")
+	cmd.Flags().DurationVar(&resyncPeriod, "resync-period", 10*time.Minute, "Full resync period for the Pod informer")
+	cmd.Flags().IntVar(&workerCount, "worker-count", 2, "Number of reconcile workers processing the queue")
+	cmd.Flags().BoolVar(&leaderElect, "leader-elect", false, "Enable leader election so only one replica reconciles at a time")
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", ":8080", "Address to serve /metrics on")
+	cmd.Flags().StringVar(&healthAddr, "health-addr", ":8081", "Address to serve /healthz on")
+	cmd.Flags().StringVar(&annotationsFile, "annotations-file", "", "YAML/JSON file of annotations/labels to apply (see annotationConfig)")
+	cmd.Flags().StringArrayVar(&annotationFlags, "annotation", nil, "Annotation to apply, as key=value (repeatable, overrides --annotations-file)")
+	cmd.Flags().StringArrayVar(&podLabelFlags, "pod-label", nil, "Label to apply, as key=value (repeatable, overrides --annotations-file)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the JSON patch for each matching Pod instead of applying it")
+	cmd.Flags().StringArrayVar(&ownerKinds, "owner-kind", nil, "Workload kind a Pod must be (transitively) owned by, e.g. CloneSet, StatefulSet, Deployment (repeatable, default CloneSet)")
+
+	cmd.MarkFlagRequired("file")
+	cmd.MarkFlagRequired("label")
+
+	return cmd
+}
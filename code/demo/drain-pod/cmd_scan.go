@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kbsonlong/pod-annotator/pkg/rules"
+)
+
+// rulesConfigMapKey is the ConfigMap data key `scan` reads rule
+// definitions from when given --rules-configmap.
+const rulesConfigMapKey = "rules.yaml"
+
+// newScanCommand builds the `pod-annotator scan` subcommand. Borrowing the
+// Node Problem Detector pattern, it repeatedly evaluates a set of
+// JSONPath-over-status rules (see pkg/rules) against Pods on the node list
+// and annotates the ones that match.
+func newScanCommand() *cobra.Command {
+	var (
+		scanFile           string
+		scanLabel          string
+		scanRulesFile      string
+		scanRulesConfigMap string
+		scanInterval       time.Duration
+		scanDryRun         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Periodically evaluate health rules against Pods and annotate the ones that match",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := clientcmd.BuildConfigFromFlags("", os.Getenv("KUBECONFIG"))
+			if err != nil {
+				config, err = rest.InClusterConfig()
+				if err != nil {
+					return fmt.Errorf("loading kubeconfig: %w", err)
+				}
+			}
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("building clientset: %w", err)
+			}
+
+			nodes, err := newNodeSet(scanFile)
+			if err != nil {
+				return fmt.Errorf("loading node list: %w", err)
+			}
+			defer nodes.Close()
+
+			ctx := signalContext()
+			ticker := time.NewTicker(scanInterval)
+			defer ticker.Stop()
+
+			for {
+				ruleSet, err := loadScanRules(ctx, clientset, scanRulesFile, scanRulesConfigMap)
+				if err != nil {
+					return fmt.Errorf("loading rules: %w", err)
+				}
+
+				if err := runScan(ctx, clientset, rules.NewEngine(ruleSet), nodes, scanLabel, scanDryRun); err != nil {
+					fmt.Printf("scan: error: %v\n", err)
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&scanFile, "file", "f", "", "Filename containing the node list (required)")
+	cmd.Flags().StringVarP(&scanLabel, "label", "l", "", "Label selector for filtering Pods")
+	cmd.Flags().StringVar(&scanRulesFile, "rules-file", "", "Local YAML/JSON file of rule definitions")
+	cmd.Flags().StringVar(&scanRulesConfigMap, "rules-configmap", "", "ConfigMap holding rule definitions, as namespace/name")
+	cmd.Flags().DurationVar(&scanInterval, "interval", time.Minute, "How often to re-evaluate rules against the node list")
+	cmd.Flags().BoolVar(&scanDryRun, "dry-run", false, "Print the JSON patch for each match instead of applying it")
+
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func loadScanRules(ctx context.Context, clientset *kubernetes.Clientset, file, configMapRef string) ([]rules.Rule, error) {
+	switch {
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		return rules.LoadRules(data)
+
+	case configMapRef != "":
+		namespace, name, ok := strings.Cut(configMapRef, "/")
+		if !ok {
+			return nil, fmt.Errorf("--rules-configmap must be namespace/name, got %q", configMapRef)
+		}
+		cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		data, ok := cm.Data[rulesConfigMapKey]
+		if !ok {
+			return nil, fmt.Errorf("configmap %s/%s has no %q key", namespace, name, rulesConfigMapKey)
+		}
+		return rules.LoadRules([]byte(data))
+
+	default:
+		return nil, fmt.Errorf("one of --rules-file or --rules-configmap is required")
+	}
+}
+
+// runScan lists the Pods currently on the node list, evaluates engine
+// against each, annotates the ones with at least one match, and prints a
+// TIME/NAME/NAMESPACE/KIND/RESULT report with one row per matching Pod,
+// listing every rule it matched in RESULT.
+func runScan(ctx context.Context, clientset *kubernetes.Clientset, engine *rules.Engine, nodes *nodeSet, labelSelector string, dryRun bool) error {
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return fmt.Errorf("listing pods: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tNAME\tNAMESPACE\tKIND\tRESULT")
+	defer w.Flush()
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !nodes.Has(pod.Spec.NodeName) {
+			continue
+		}
+
+		matches, err := engine.Evaluate(pod)
+		if err != nil {
+			return fmt.Errorf("evaluating pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		annotations := map[string]string{}
+		results := make([]string, 0, len(matches))
+		for _, match := range matches {
+			for k, v := range match.Rule.Annotation {
+				annotations[k] = v
+			}
+			results = append(results, fmt.Sprintf("%s(%s)", match.Rule.Name, match.Rule.Severity))
+		}
+
+		if err := addAnnotation(ctx, clientset, pod.Namespace, pod.Name, annotations, nil, dryRun); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", time.Now().Format(time.RFC3339), pod.Name, pod.Namespace, "Pod", strings.Join(results, ","))
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+//go:embed policy.schema.json
+var policySchemaJSON []byte
+
+// newValidateCommand builds the `pod-annotator validate` subcommand, which
+// checks one or more policy documents against policy.schema.json without
+// touching the cluster.
+func newValidateCommand() *cobra.Command {
+	var policyFiles []string
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate PodAnnotationPolicy documents against the JSON schema",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			docs, err := loadPolicyDocuments(policyFiles)
+			if err != nil {
+				return err
+			}
+
+			schema := gojsonschema.NewBytesLoader(policySchemaJSON)
+
+			var invalid int
+			for _, doc := range docs {
+				// Validate the document exactly as the user wrote it, not
+				// a re-encoded copy of the typed struct - round-tripping
+				// through PodAnnotationPolicy would silently drop any
+				// field the struct doesn't know about, defeating the
+				// point of schema-checking for authoring mistakes.
+				result, err := gojsonschema.Validate(schema, gojsonschema.NewBytesLoader(doc.Raw))
+				if err != nil {
+					return fmt.Errorf("validating policy %q: %w", doc.Policy.Metadata.Name, err)
+				}
+
+				if result.Valid() {
+					fmt.Printf("%s: valid\n", doc.Policy.Metadata.Name)
+					continue
+				}
+
+				invalid++
+				fmt.Printf("%s: invalid\n", doc.Policy.Metadata.Name)
+				for _, issue := range result.Errors() {
+					fmt.Printf("  - %s\n", issue)
+				}
+			}
+
+			if invalid > 0 {
+				return fmt.Errorf("%d policy document(s) failed validation", invalid)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&policyFiles, "file", "f", nil, "Policy file or directory to validate (repeatable)")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// annotationConfig describes the annotations and labels pod-annotator
+// applies to a matching Pod. Values may contain Go templates that are
+// expanded per-Pod, see renderAnnotations.
+type annotationConfig struct {
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// loadAnnotationConfig reads an annotationConfig from a YAML or JSON file.
+// An empty path returns an empty config so --annotation/--pod-label can be
+// used on their own without --annotations-file.
+func loadAnnotationConfig(path string) (*annotationConfig, error) {
+	cfg := &annotationConfig{
+		Annotations: map[string]string{},
+		Labels:      map[string]string{},
+	}
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if cfg.Annotations == nil {
+		cfg.Annotations = map[string]string{}
+	}
+	if cfg.Labels == nil {
+		cfg.Labels = map[string]string{}
+	}
+	return cfg, nil
+}
+
+// applyAnnotationFlags merges repeatable "key=value" pairs from
+// --annotation on top of cfg, overriding any value loaded from file.
+func (cfg *annotationConfig) applyAnnotationFlags(pairs []string) error {
+	return mergeKeyValuePairs(cfg.Annotations, pairs)
+}
+
+// applyLabelFlags merges repeatable "key=value" pairs from --pod-label on
+// top of cfg, overriding any value loaded from file.
+func (cfg *annotationConfig) applyLabelFlags(pairs []string) error {
+	return mergeKeyValuePairs(cfg.Labels, pairs)
+}
+
+func mergeKeyValuePairs(dst map[string]string, pairs []string) error {
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid key=value pair %q", pair)
+		}
+		dst[key] = value
+	}
+	return nil
+}
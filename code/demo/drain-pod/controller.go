@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// controller watches Pods across the cluster, filters them down to the
+// configured node list and label selector, and reconciles annotations onto
+// the ones owned by one of the configured workload kinds (see
+// ownerResolver). It follows the standard client-go
+// informer/workqueue pattern so reconciles are level-triggered and safe to
+// run with multiple workers.
+type controller struct {
+	clientset *kubernetes.Clientset
+	nodes     *nodeSet
+	config    *annotationConfig
+	owners    *ownerResolver
+	dryRun    bool
+
+	informerFactory informers.SharedInformerFactory
+	podInformer     cache.SharedIndexInformer
+
+	queue       workqueue.RateLimitingInterface
+	workerCount int
+}
+
+func newController(clientset *kubernetes.Clientset, nodes *nodeSet, cfg *annotationConfig, owners *ownerResolver, dryRun bool, labelSelector string, resyncPeriod time.Duration, workerCount int) (*controller, error) {
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, resyncPeriod,
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = labelSelector
+		}),
+	)
+	podInformer := factory.Core().V1().Pods().Informer()
+
+	c := &controller{
+		clientset:       clientset,
+		nodes:           nodes,
+		config:          cfg,
+		owners:          owners,
+		dryRun:          dryRun,
+		informerFactory: factory,
+		podInformer:     podInformer,
+		queue:           workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		workerCount:     workerCount,
+	}
+
+	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueue,
+		UpdateFunc: func(_, newObj interface{}) { c.enqueue(newObj) },
+	})
+
+	return c, nil
+}
+
+func (c *controller) enqueue(obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return
+	}
+	if !c.nodes.Has(pod.Spec.NodeName) {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(pod)
+	if err != nil {
+		return
+	}
+	c.queue.Add(key)
+}
+
+// Run starts the informer and worker goroutines and blocks until ctx is
+// cancelled.
+func (c *controller) Run(ctx context.Context) {
+	defer c.queue.ShutDown()
+
+	go c.informerFactory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), c.podInformer.HasSynced) {
+		fmt.Println("pod-annotator: timed out waiting for informer cache sync")
+		return
+	}
+
+	for i := 0; i < c.workerCount; i++ {
+		go wait.Until(func() { c.runWorker(ctx) }, time.Second, ctx.Done())
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *controller) processNextItem(ctx context.Context) bool {
+	key, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.reconcile(ctx, key.(string)); err != nil {
+		reconcileErrorsTotal.Inc()
+		fmt.Printf("pod-annotator: error reconciling %s: %v\n", key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	return true
+}
+
+// reconcile re-reads the Pod from the API, re-checks that it still belongs
+// to a configured owner kind on a watched node, and patches its annotations. It is
+// idempotent: re-running it against an already-annotated Pod is a no-op
+// from the caller's perspective, and Patch conflicts are retried.
+func (c *controller) reconcile(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !c.nodes.Has(pod.Spec.NodeName) {
+		return nil
+	}
+	owned, err := c.owners.Matches(ctx, pod)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return nil
+	}
+
+	annotations, labels, err := renderAnnotations(c.config, pod)
+	if err != nil {
+		return err
+	}
+
+	err = wait.PollImmediateWithContext(ctx, 100*time.Millisecond, 2*time.Second, func(ctx context.Context) (bool, error) {
+		patchErr := addAnnotation(ctx, c.clientset, namespace, name, annotations, labels, c.dryRun)
+		if apierrors.IsConflict(patchErr) {
+			return false, nil
+		}
+		return patchErr == nil, patchErr
+	})
+	if err != nil {
+		return err
+	}
+
+	reconcileTotal.Inc()
+	return nil
+}
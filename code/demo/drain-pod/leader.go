@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const leaderElectionName = "pod-annotator-leader"
+
+// runWithLeaderElection wraps ctrl.Run in a leader-election loop so that
+// when multiple replicas of pod-annotator are deployed, only the elected
+// leader reconciles Pods at any given time.
+func runWithLeaderElection(ctx context.Context, clientset *kubernetes.Clientset, ctrl *controller) error {
+	namespace := os.Getenv("POD_NAMESPACE")
+	if namespace == "" {
+		namespace = "default"
+	}
+	identity, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("determining leader election identity: %w", err)
+	}
+
+	lock, err := resourcelock.New(
+		resourcelock.LeasesResourceLock,
+		namespace,
+		leaderElectionName,
+		clientset.CoreV1(),
+		clientset.CoordinationV1(),
+		resourcelock.ResourceLockConfig{Identity: identity},
+	)
+	if err != nil {
+		return fmt.Errorf("building leader election lock: %w", err)
+	}
+
+	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		ReleaseOnCancel: true,
+		LeaseDuration:   15 * time.Second,
+		RenewDeadline:   10 * time.Second,
+		RetryPeriod:     2 * time.Second,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				ctrl.Run(ctx)
+			},
+			OnStoppedLeading: func() {
+				fmt.Printf("pod-annotator: %s stopped leading\n", identity)
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					fmt.Printf("pod-annotator: new leader elected: %s\n", leader)
+				}
+			},
+		},
+	})
+
+	return nil
+}
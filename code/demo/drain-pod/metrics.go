@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	reconcileTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pod_annotator_reconcile_total",
+		Help: "Total number of Pods successfully reconciled.",
+	})
+	reconcileErrorsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pod_annotator_reconcile_errors_total",
+		Help: "Total number of reconcile attempts that returned an error.",
+	})
+)
+
+// serveMetricsAndHealth starts the /metrics and /healthz HTTP endpoints in
+// background goroutines. Failures are logged rather than fatal, since losing
+// observability shouldn't stop the controller from reconciling Pods.
+func serveMetricsAndHealth(metricsAddr, healthAddr string, ctrl *controller) {
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		if err := http.ListenAndServe(metricsAddr, metricsMux); err != nil {
+			fmt.Printf("pod-annotator: metrics server exited: %v\n", err)
+		}
+	}()
+
+	healthMux := http.NewServeMux()
+	healthMux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if !ctrl.podInformer.HasSynced() {
+			http.Error(w, "informer cache not synced", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+	go func() {
+		if err := http.ListenAndServe(healthAddr, healthMux); err != nil {
+			fmt.Printf("pod-annotator: health server exited: %v\n", err)
+		}
+	}()
+}
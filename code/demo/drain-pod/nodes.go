@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// nodeSet tracks the set of node names loaded from filename and keeps it
+// up to date across the lifetime of the controller. It is reloaded whenever
+// the file changes on disk or the process receives SIGHUP, so operators can
+// rebalance a running replica without restarting it.
+type nodeSet struct {
+	filename string
+
+	mu    sync.RWMutex
+	names map[string]struct{}
+
+	watcher *fsnotify.Watcher
+	sigCh   chan os.Signal
+	stopCh  chan struct{}
+}
+
+func newNodeSet(filename string) (*nodeSet, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("creating fsnotify watcher: %w", err)
+	}
+	if err := watcher.Add(filename); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("watching %s: %w", filename, err)
+	}
+
+	ns := &nodeSet{
+		filename: filename,
+		watcher:  watcher,
+		sigCh:    make(chan os.Signal, 1),
+		stopCh:   make(chan struct{}),
+	}
+
+	if err := ns.reload(); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	signal.Notify(ns.sigCh, syscall.SIGHUP)
+	go ns.watch()
+
+	return ns, nil
+}
+
+func (ns *nodeSet) watch() {
+	for {
+		select {
+		case <-ns.stopCh:
+			return
+		case _, ok := <-ns.watcher.Events:
+			if !ok {
+				return
+			}
+			if err := ns.reload(); err != nil {
+				fmt.Printf("error reloading node list %s: %v\n", ns.filename, err)
+			}
+		case <-ns.sigCh:
+			if err := ns.reload(); err != nil {
+				fmt.Printf("error reloading node list %s on SIGHUP: %v\n", ns.filename, err)
+			}
+		}
+	}
+}
+
+func (ns *nodeSet) reload() error {
+	names, err := readNodeNames(ns.filename)
+	if err != nil {
+		return err
+	}
+
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+
+	ns.mu.Lock()
+	ns.names = set
+	ns.mu.Unlock()
+
+	return nil
+}
+
+// readNodeNames reads a newline-separated node list file, skipping blank
+// lines, without keeping it open or watching it for changes.
+func readNodeNames(filename string) ([]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		names = append(names, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// staticNodeSet is a non-watching nodeSet backed by a fixed list, used by
+// `apply` where a policy's nodeSelector is read once per run.
+type staticNodeSet struct {
+	names map[string]struct{}
+}
+
+func newStaticNodeSet(names []string) *staticNodeSet {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+	return &staticNodeSet{names: set}
+}
+
+func (ns *staticNodeSet) Has(nodeName string) bool {
+	_, ok := ns.names[nodeName]
+	return ok
+}
+
+// Has reports whether nodeName is currently in the configured node list.
+func (ns *nodeSet) Has(nodeName string) bool {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
+	_, ok := ns.names[nodeName]
+	return ok
+}
+
+// Close stops watching the underlying file and releases the SIGHUP handler.
+func (ns *nodeSet) Close() {
+	close(ns.stopCh)
+	signal.Stop(ns.sigCh)
+	ns.watcher.Close()
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// OwnerMatcher decides whether a Pod is (transitively) owned by a
+// particular workload kind. Implementations are registered in
+// ownerMatchers by the GVK they handle - see registerOwnerMatcher. The
+// interface, the registry and registerOwnerMatcher all live in package
+// main, and the registry and its register function are unexported, so new
+// kinds are added by code inside this package; other modules cannot import
+// them.
+type OwnerMatcher interface {
+	// GVK identifies the workload kind this matcher resolves.
+	GVK() schema.GroupVersionKind
+	// Resolve walks pod's owner chain, following intermediate owners such
+	// as ReplicaSets via dyn when needed, and reports whether a controller
+	// of this kind owns it.
+	Resolve(ctx context.Context, dyn dynamic.Interface, pod *v1.Pod) (bool, error)
+}
+
+// ownerMatchers is keyed by Kind rather than the full GVK so --owner-kind
+// can stay a short, human-typed flag value.
+var ownerMatchers = map[string]OwnerMatcher{}
+
+// registerOwnerMatcher adds m to the registry, keyed by its Kind. A later
+// call for the same Kind replaces an earlier one, so an init() in this
+// package can override a built-in matcher before newOwnerResolver runs.
+func registerOwnerMatcher(m OwnerMatcher) {
+	ownerMatchers[m.GVK().Kind] = m
+}
+
+func init() {
+	registerOwnerMatcher(directMatcher{gvk: schema.GroupVersionKind{Group: "apps.kruise.io", Version: "v1alpha1", Kind: "CloneSet"}})
+	registerOwnerMatcher(directMatcher{gvk: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}})
+	registerOwnerMatcher(directMatcher{gvk: schema.GroupVersionKind{Group: "apps.kruise.io", Version: "v1beta1", Kind: "AdvancedStatefulSet"}})
+	registerOwnerMatcher(transitiveMatcher{
+		gvk:     schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		viaGVR:  schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"},
+		viaKind: "ReplicaSet",
+	})
+}
+
+// directMatcher matches a Pod whose own OwnerReferences names the kind.
+type directMatcher struct {
+	gvk schema.GroupVersionKind
+}
+
+func (m directMatcher) GVK() schema.GroupVersionKind { return m.gvk }
+
+func (m directMatcher) Resolve(_ context.Context, _ dynamic.Interface, pod *v1.Pod) (bool, error) {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == m.gvk.Kind {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// transitiveMatcher matches a Pod owned by an intermediate resource (e.g. a
+// ReplicaSet) that is itself owned by the target kind, resolving the
+// intermediate object via the dynamic client.
+type transitiveMatcher struct {
+	gvk     schema.GroupVersionKind
+	viaGVR  schema.GroupVersionResource
+	viaKind string
+}
+
+func (m transitiveMatcher) GVK() schema.GroupVersionKind { return m.gvk }
+
+func (m transitiveMatcher) Resolve(ctx context.Context, dyn dynamic.Interface, pod *v1.Pod) (bool, error) {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == m.gvk.Kind {
+			return true, nil
+		}
+		if ref.Kind != m.viaKind {
+			continue
+		}
+
+		intermediate, err := dyn.Resource(m.viaGVR).Namespace(pod.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, parentRef := range intermediate.GetOwnerReferences() {
+			if parentRef.Kind == m.gvk.Kind {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// ownerResolver checks a Pod against a configured list of workload kinds,
+// in order, stopping at the first match.
+type ownerResolver struct {
+	dyn   dynamic.Interface
+	kinds []string
+}
+
+func newOwnerResolver(dyn dynamic.Interface, kinds []string) *ownerResolver {
+	if len(kinds) == 0 {
+		kinds = []string{"CloneSet"}
+	}
+	return &ownerResolver{dyn: dyn, kinds: kinds}
+}
+
+// Matches reports whether pod is owned by one of the configured kinds. RBAC
+// errors reading an intermediate owner are treated as "not matched" rather
+// than failing the whole reconcile, since a Pod owned by a kind the
+// controller can't read is indistinguishable from one that isn't owned by
+// it at all.
+func (r *ownerResolver) Matches(ctx context.Context, pod *v1.Pod) (bool, error) {
+	for _, kind := range r.kinds {
+		matcher, ok := ownerMatchers[kind]
+		if !ok {
+			return false, fmt.Errorf("no OwnerMatcher registered for kind %q", kind)
+		}
+
+		matched, err := matcher.Resolve(ctx, r.dyn, pod)
+		if err != nil {
+			if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
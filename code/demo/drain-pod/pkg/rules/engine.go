@@ -0,0 +1,39 @@
+package rules
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Engine evaluates a fixed set of Rules against Pods.
+type Engine struct {
+	rules []Rule
+}
+
+// NewEngine builds an Engine over the given rules.
+func NewEngine(rules []Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// Match pairs a Pod with the Rule it satisfied.
+type Match struct {
+	Rule Rule
+	Pod  *corev1.Pod
+}
+
+// Evaluate runs every rule against pod and returns the ones that matched,
+// in rule order.
+func (e *Engine) Evaluate(pod *corev1.Pod) ([]Match, error) {
+	var matches []Match
+	for _, rule := range e.rules {
+		ok, err := rule.Evaluate(pod)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+		if ok {
+			matches = append(matches, Match{Rule: rule, Pod: pod})
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,209 @@
+// Package rules implements a small, embeddable rule engine for evaluating
+// JSONPath conditions over a Pod's status and deciding which annotation a
+// matching Pod should receive. It backs pod-annotator's `scan` subcommand,
+// but is exported so other projects can reuse the same rule format and
+// evaluator without depending on the pod-annotator CLI.
+package rules
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+// Operator is a comparison applied between a Condition's JSONPath result
+// and its Value.
+type Operator string
+
+const (
+	OperatorEqual              Operator = "eq"
+	OperatorNotEqual           Operator = "ne"
+	OperatorGreaterThan        Operator = "gt"
+	OperatorGreaterThanOrEqual Operator = "gte"
+	OperatorLessThan           Operator = "lt"
+	OperatorLessThanOrEqual    Operator = "lte"
+)
+
+// Condition compares the value found at JSONPath (evaluated against the
+// Pod's corev1.PodStatus) to Value using Operator.
+type Condition struct {
+	JSONPath string   `json:"jsonPath"`
+	Operator Operator `json:"operator"`
+	Value    string   `json:"value"`
+}
+
+// Rule describes a single health check: an optional selector narrowing
+// which Pods it applies to, a Condition evaluated against the Pod's
+// status, and the annotation to apply when the condition holds.
+type Rule struct {
+	Name          string            `json:"name"`
+	LabelSelector string            `json:"labelSelector,omitempty"`
+	FieldSelector string            `json:"fieldSelector,omitempty"`
+	Condition     Condition         `json:"condition"`
+	Annotation    map[string]string `json:"annotation"`
+	Severity      string            `json:"severity,omitempty"`
+}
+
+// LoadRules parses a YAML or JSON list of Rules, as found in a rules file
+// or a ConfigMap data key.
+func LoadRules(data []byte) ([]Rule, error) {
+	var loaded []Rule
+	if err := yaml.Unmarshal(data, &loaded); err != nil {
+		return nil, fmt.Errorf("parsing rules: %w", err)
+	}
+	for i, rule := range loaded {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d: name is required", i)
+		}
+	}
+	return loaded, nil
+}
+
+// Evaluate reports whether pod is in scope for the rule (per its
+// LabelSelector/FieldSelector, if set) and satisfies the rule's Condition.
+// A Pod missing the field the rule looks for is treated as not matching
+// rather than an error, since most conditions (e.g. restartCount on a
+// specific container) are only present some of the time.
+func (r Rule) Evaluate(pod *corev1.Pod) (bool, error) {
+	inScope, err := r.inScope(pod)
+	if err != nil {
+		return false, err
+	}
+	if !inScope {
+		return false, nil
+	}
+
+	jp := jsonpath.New(r.Name)
+	if err := jp.Parse(wrapJSONPath(r.Condition.JSONPath)); err != nil {
+		return false, fmt.Errorf("parsing jsonPath %q: %w", r.Condition.JSONPath, err)
+	}
+
+	results, err := jp.FindResults(pod.Status)
+	if err != nil {
+		return false, nil
+	}
+
+	for _, set := range results {
+		for _, value := range set {
+			matched, err := r.Condition.matches(value.Interface())
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// inScope reports whether pod matches the rule's LabelSelector and
+// FieldSelector, if set. An empty selector matches every Pod.
+func (r Rule) inScope(pod *corev1.Pod) (bool, error) {
+	if r.LabelSelector != "" {
+		selector, err := labels.Parse(r.LabelSelector)
+		if err != nil {
+			return false, fmt.Errorf("parsing labelSelector %q: %w", r.LabelSelector, err)
+		}
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			return false, nil
+		}
+	}
+
+	if r.FieldSelector != "" {
+		selector, err := fields.ParseSelector(r.FieldSelector)
+		if err != nil {
+			return false, fmt.Errorf("parsing fieldSelector %q: %w", r.FieldSelector, err)
+		}
+		if !selector.Matches(podFieldSet(pod)) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// podFieldSet exposes the subset of a Pod's fields that the API server
+// itself supports in field selectors, so rule authors can write
+// fieldSelector the same way they'd pass --field-selector to kubectl.
+func podFieldSet(pod *corev1.Pod) fields.Set {
+	return fields.Set{
+		"metadata.name":      pod.Name,
+		"metadata.namespace": pod.Namespace,
+		"spec.nodeName":      pod.Spec.NodeName,
+		"spec.restartPolicy": string(pod.Spec.RestartPolicy),
+		"spec.schedulerName": pod.Spec.SchedulerName,
+		"status.phase":       string(pod.Status.Phase),
+		"status.podIP":       pod.Status.PodIP,
+	}
+}
+
+func (c Condition) matches(actual interface{}) (bool, error) {
+	switch c.Operator {
+	case OperatorEqual:
+		return fmt.Sprintf("%v", actual) == c.Value, nil
+	case OperatorNotEqual:
+		return fmt.Sprintf("%v", actual) != c.Value, nil
+	case OperatorGreaterThan, OperatorGreaterThanOrEqual, OperatorLessThan, OperatorLessThanOrEqual:
+		return c.compareNumeric(actual)
+	default:
+		return false, fmt.Errorf("unknown operator %q", c.Operator)
+	}
+}
+
+func (c Condition) compareNumeric(actual interface{}) (bool, error) {
+	actualNum, err := toFloat64(actual)
+	if err != nil {
+		return false, err
+	}
+	wantNum, err := strconv.ParseFloat(c.Value, 64)
+	if err != nil {
+		return false, fmt.Errorf("value %q is not numeric: %w", c.Value, err)
+	}
+
+	switch c.Operator {
+	case OperatorGreaterThan:
+		return actualNum > wantNum, nil
+	case OperatorGreaterThanOrEqual:
+		return actualNum >= wantNum, nil
+	case OperatorLessThan:
+		return actualNum < wantNum, nil
+	case OperatorLessThanOrEqual:
+		return actualNum <= wantNum, nil
+	default:
+		return false, fmt.Errorf("unknown operator %q", c.Operator)
+	}
+}
+
+func toFloat64(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	default:
+		parsed, err := strconv.ParseFloat(fmt.Sprintf("%v", v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("value %v is not numeric", v)
+		}
+		return parsed, nil
+	}
+}
+
+func wrapJSONPath(path string) string {
+	if len(path) > 1 && path[0] == '{' {
+		return path
+	}
+	return "{" + path + "}"
+}
@@ -0,0 +1,164 @@
+package rules
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func podWithRestarts(count int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-0",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "web"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node-a",
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{RestartCount: count}},
+		},
+	}
+}
+
+func TestConditionOperators(t *testing.T) {
+	tests := []struct {
+		name     string
+		operator Operator
+		value    string
+		restarts int32
+		want     bool
+	}{
+		{"eq matches", OperatorEqual, "5", 5, true},
+		{"eq does not match", OperatorEqual, "5", 6, false},
+		{"ne matches", OperatorNotEqual, "5", 6, true},
+		{"ne does not match", OperatorNotEqual, "5", 5, false},
+		{"gt matches", OperatorGreaterThan, "5", 6, true},
+		{"gt does not match", OperatorGreaterThan, "5", 5, false},
+		{"gte matches equal", OperatorGreaterThanOrEqual, "5", 5, true},
+		{"gte does not match", OperatorGreaterThanOrEqual, "5", 4, false},
+		{"lt matches", OperatorLessThan, "5", 4, true},
+		{"lt does not match", OperatorLessThan, "5", 5, false},
+		{"lte matches equal", OperatorLessThanOrEqual, "5", 5, true},
+		{"lte does not match", OperatorLessThanOrEqual, "5", 6, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := Rule{
+				Name: "restart-count",
+				Condition: Condition{
+					JSONPath: ".containerStatuses[0].restartCount",
+					Operator: tt.operator,
+					Value:    tt.value,
+				},
+			}
+
+			got, err := rule.Evaluate(podWithRestarts(tt.restarts))
+			if err != nil {
+				t.Fatalf("Evaluate returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleEvaluateLabelSelectorScoping(t *testing.T) {
+	rule := Rule{
+		Name:          "restart-count",
+		LabelSelector: "app=other",
+		Condition: Condition{
+			JSONPath: ".containerStatuses[0].restartCount",
+			Operator: OperatorGreaterThan,
+			Value:    "0",
+		},
+	}
+
+	matched, err := rule.Evaluate(podWithRestarts(5))
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+	if matched {
+		t.Error("Evaluate() = true, want false: pod labels don't satisfy labelSelector")
+	}
+
+	rule.LabelSelector = "app=web"
+	matched, err = rule.Evaluate(podWithRestarts(5))
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+	if !matched {
+		t.Error("Evaluate() = false, want true: pod labels satisfy labelSelector")
+	}
+}
+
+func TestRuleEvaluateFieldSelectorScoping(t *testing.T) {
+	rule := Rule{
+		Name:          "restart-count",
+		FieldSelector: "spec.nodeName=node-b",
+		Condition: Condition{
+			JSONPath: ".containerStatuses[0].restartCount",
+			Operator: OperatorGreaterThan,
+			Value:    "0",
+		},
+	}
+
+	matched, err := rule.Evaluate(podWithRestarts(5))
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+	if matched {
+		t.Error("Evaluate() = true, want false: pod is on node-a, not node-b")
+	}
+
+	rule.FieldSelector = "spec.nodeName=node-a"
+	matched, err = rule.Evaluate(podWithRestarts(5))
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+	if !matched {
+		t.Error("Evaluate() = false, want true: pod is on node-a")
+	}
+}
+
+func TestRuleEvaluateMalformedJSONPath(t *testing.T) {
+	rule := Rule{
+		Name: "bad-path",
+		Condition: Condition{
+			JSONPath: ".status.containerStatuses[0",
+			Operator: OperatorGreaterThan,
+			Value:    "0",
+		},
+	}
+
+	if _, err := rule.Evaluate(podWithRestarts(1)); err == nil {
+		t.Fatal("Evaluate() returned no error for a malformed jsonPath")
+	}
+}
+
+func TestRuleEvaluateNonNumericValue(t *testing.T) {
+	rule := Rule{
+		Name: "restart-count",
+		Condition: Condition{
+			JSONPath: ".containerStatuses[0].restartCount",
+			Operator: OperatorGreaterThan,
+			Value:    "not-a-number",
+		},
+	}
+
+	if _, err := rule.Evaluate(podWithRestarts(1)); err == nil {
+		t.Fatal("Evaluate() returned no error for a non-numeric condition value")
+	}
+}
+
+func TestLoadRulesRequiresName(t *testing.T) {
+	_, err := LoadRules([]byte(`- condition: {jsonPath: ".status.phase", operator: eq, value: Running}`))
+	if err == nil {
+		t.Fatal("LoadRules() returned no error for a rule missing a name")
+	}
+}
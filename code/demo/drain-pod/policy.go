@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	apiyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	policyAPIVersion = "pod-annotator.example.com/v1alpha1"
+	policyKind       = "PodAnnotationPolicy"
+)
+
+// PodAnnotationPolicy is a declarative description of what a `pod-annotator
+// run` invocation would do: which Pods to match and which annotations/
+// labels to apply to them. `generate` emits one from flags; `apply` reads
+// one or more of them back.
+type PodAnnotationPolicy struct {
+	APIVersion string                  `json:"apiVersion"`
+	Kind       string                  `json:"kind"`
+	Metadata   PolicyMetadata          `json:"metadata"`
+	Spec       PodAnnotationPolicySpec `json:"spec"`
+}
+
+type PolicyMetadata struct {
+	Name string `json:"name"`
+}
+
+type PodAnnotationPolicySpec struct {
+	NodeSelector []string          `json:"nodeSelector,omitempty"`
+	PodSelector  string            `json:"podSelector,omitempty"`
+	OwnerKinds   []string          `json:"ownerKinds,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+}
+
+// validate checks the required fields of a policy without touching the
+// cluster. `apply` uses it to refuse an invalid policy; the `validate`
+// subcommand checks documents against policy.schema.json instead.
+func (p PodAnnotationPolicy) validate() error {
+	if p.APIVersion != policyAPIVersion {
+		return fmt.Errorf("apiVersion must be %q, got %q", policyAPIVersion, p.APIVersion)
+	}
+	if p.Kind != policyKind {
+		return fmt.Errorf("kind must be %q, got %q", policyKind, p.Kind)
+	}
+	if p.Metadata.Name == "" {
+		return fmt.Errorf("metadata.name is required")
+	}
+	if p.Spec.PodSelector == "" {
+		return fmt.Errorf("spec.podSelector is required")
+	}
+	if len(p.Spec.NodeSelector) == 0 {
+		return fmt.Errorf("spec.nodeSelector must list at least one node")
+	}
+	return nil
+}
+
+// PolicyDocument pairs a parsed PodAnnotationPolicy with the exact JSON
+// bytes it was decoded from, so callers that need to validate the document
+// as the user wrote it (e.g. against policy.schema.json) aren't stuck with
+// a copy that's already been through the Go struct's lossy round-trip.
+type PolicyDocument struct {
+	Raw    json.RawMessage
+	Policy PodAnnotationPolicy
+}
+
+// loadPolicies reads PodAnnotationPolicy documents from the given files and
+// directories, discarding the raw document bytes. Most callers only need
+// the parsed policies; use loadPolicyDocuments when the raw bytes matter.
+func loadPolicies(paths []string) ([]PodAnnotationPolicy, error) {
+	docs, err := loadPolicyDocuments(paths)
+	if err != nil {
+		return nil, err
+	}
+	policies := make([]PodAnnotationPolicy, len(docs))
+	for i, doc := range docs {
+		policies[i] = doc.Policy
+	}
+	return policies, nil
+}
+
+// loadPolicyDocuments reads PodAnnotationPolicy documents from the given
+// files and directories. Directories are expanded to their immediate
+// *.yaml/*.yml/*.json entries; each file may contain multiple
+// "---"-separated documents.
+func loadPolicyDocuments(paths []string) ([]PolicyDocument, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for _, entry := range entries {
+			switch filepath.Ext(entry.Name()) {
+			case ".yaml", ".yml", ".json":
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	var docs []PolicyDocument
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", file, err)
+		}
+
+		decoder := apiyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+		for {
+			var raw yamlRawDoc
+			if err := decoder.Decode(&raw); err != nil {
+				if err == io.EOF {
+					break
+				}
+				return nil, fmt.Errorf("parsing %s: %w", file, err)
+			}
+			if len(raw) == 0 {
+				continue
+			}
+
+			var policy PodAnnotationPolicy
+			if err := yaml.Unmarshal(raw, &policy); err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", file, err)
+			}
+			docs = append(docs, PolicyDocument{Raw: json.RawMessage(raw), Policy: policy})
+		}
+	}
+
+	return docs, nil
+}
+
+// yamlRawDoc lets us pull each document out of decoder.Decode as raw bytes
+// (via its json.Unmarshaler implementation) before unmarshalling it properly with
+// sigs.k8s.io/yaml, so PodAnnotationPolicy only needs plain `json` tags.
+type yamlRawDoc []byte
+
+func (d *yamlRawDoc) UnmarshalJSON(data []byte) error {
+	*d = append((*d)[0:0], data...)
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// templateData is the context exposed to annotation/label value templates,
+// e.g. "{{ .Pod.Name }}", "{{ .Node.Name }}" or "{{ .Pod.Labels.app }}".
+type templateData struct {
+	Pod  *v1.Pod
+	Node nodeData
+}
+
+type nodeData struct {
+	Name string
+}
+
+// renderAnnotations expands the Go templates in cfg's annotation and label
+// values against pod, returning the concrete key/value pairs to apply.
+func renderAnnotations(cfg *annotationConfig, pod *v1.Pod) (annotations, labels map[string]string, err error) {
+	data := templateData{Pod: pod, Node: nodeData{Name: pod.Spec.NodeName}}
+
+	if annotations, err = renderValues(cfg.Annotations, data); err != nil {
+		return nil, nil, err
+	}
+	if labels, err = renderValues(cfg.Labels, data); err != nil {
+		return nil, nil, err
+	}
+	return annotations, labels, nil
+}
+
+func renderValues(values map[string]string, data templateData) (map[string]string, error) {
+	rendered := make(map[string]string, len(values))
+	for key, raw := range values {
+		tmpl, err := template.New(key).Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parsing template for %q: %w", key, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("executing template for %q: %w", key, err)
+		}
+		rendered[key] = buf.String()
+	}
+	return rendered, nil
+}